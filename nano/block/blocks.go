@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"errors"
 
+	"github.com/alexbakker/gonano/nano/internal/binio"
 	"github.com/alexbakker/gonano/nano/internal/util"
 	"github.com/alexbakker/gonano/nano/wallet"
 )
@@ -17,6 +18,7 @@ const (
 	idBlockReceive
 	idBlockOpen
 	idBlockChange
+	idBlockState
 )
 
 var (
@@ -30,6 +32,7 @@ var (
 		idBlockReceive:   "RECEIVE",
 		idBlockOpen:      "OPEN",
 		idBlockChange:    "CHANGE",
+		idBlockState:     "STATE",
 	}
 )
 
@@ -93,6 +96,8 @@ func New(blockType byte) (Block, error) {
 		return new(ReceiveBlock), nil
 	case idBlockChange:
 		return new(ChangeBlock), nil
+	case idBlockState:
+		return new(StateBlock), nil
 	case idBlockNotABlock:
 		return nil, ErrNotABlock
 	default:
@@ -107,14 +112,12 @@ func Name(id byte) string {
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (b *CommonBlock) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
 
-	var err error
-	if _, err = buf.Write(b.Signature[:]); err != nil {
-		return nil, err
-	}
-
-	if err = binary.Write(buf, binary.LittleEndian, b.Work); err != nil {
-		return nil, err
+	w.WriteBytes(b.Signature[:])
+	w.WriteU64LE(uint64(b.Work))
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	return buf.Bytes(), nil
@@ -123,13 +126,12 @@ func (b *CommonBlock) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (b *CommonBlock) UnmarshalBinary(data []byte) error {
 	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
 
-	if _, err := reader.Read(b.Signature[:]); err != nil {
-		return err
-	}
-
-	if err := binary.Read(reader, binary.LittleEndian, &b.Work); err != nil {
-		return err
+	r.ReadBytes(b.Signature[:])
+	b.Work = Work(r.ReadU64LE())
+	if r.Err != nil {
+		return r.Err
 	}
 
 	return util.AssertReaderEOF(reader)
@@ -138,26 +140,22 @@ func (b *CommonBlock) UnmarshalBinary(data []byte) error {
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (b *OpenBlock) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
 
-	var err error
-	if _, err = buf.Write(b.SourceHash[:]); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.Representative); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.Address); err != nil {
-		return nil, err
+	w.WriteBytes(b.SourceHash[:])
+	w.WriteBytes(b.Representative)
+	w.WriteBytes(b.Address)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	commonBytes, err := b.Common.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	if _, err = buf.Write(commonBytes); err != nil {
-		return nil, err
+	w.WriteBytes(commonBytes)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	return buf.Bytes(), nil
@@ -166,28 +164,16 @@ func (b *OpenBlock) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (b *OpenBlock) UnmarshalBinary(data []byte) error {
 	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
 
-	var err error
-	if _, err = reader.Read(b.SourceHash[:]); err != nil {
-		return err
-	}
-
-	b.Representative = make([]byte, wallet.AddressSize)
-	if _, err = reader.Read(b.Representative); err != nil {
-		return err
-	}
-
-	b.Address = make([]byte, wallet.AddressSize)
-	if _, err = reader.Read(b.Address); err != nil {
-		return err
-	}
-
-	commonBytes := make([]byte, reader.Len())
-	if _, err = reader.Read(commonBytes); err != nil {
-		return err
+	r.ReadBytes(b.SourceHash[:])
+	b.Representative = r.ReadFull(wallet.AddressSize)
+	b.Address = r.ReadFull(wallet.AddressSize)
+	if r.Err != nil {
+		return r.Err
 	}
 
-	return b.Common.UnmarshalBinary(commonBytes)
+	return b.Common.UnmarshalBinary(r.ReadFull(reader.Len()))
 }
 
 func (b *OpenBlock) Hash() Hash {
@@ -219,26 +205,22 @@ func (b *OpenBlock) Valid() bool {
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (b *SendBlock) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
 
-	var err error
-	if _, err = buf.Write(b.PreviousHash[:]); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.Destination); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.Balance.Bytes(binary.BigEndian)); err != nil {
-		return nil, err
+	w.WriteBytes(b.PreviousHash[:])
+	w.WriteBytes(b.Destination)
+	w.WriteBytes(b.Balance.Bytes(binary.BigEndian))
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	commonBytes, err := b.Common.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	if _, err = buf.Write(commonBytes); err != nil {
-		return nil, err
+	w.WriteBytes(commonBytes)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	return buf.Bytes(), nil
@@ -247,31 +229,19 @@ func (b *SendBlock) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (b *SendBlock) UnmarshalBinary(data []byte) error {
 	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
 
-	var err error
-	if _, err = reader.Read(b.PreviousHash[:]); err != nil {
-		return err
+	r.ReadBytes(b.PreviousHash[:])
+	b.Destination = r.ReadFull(wallet.AddressSize)
+	balance := r.ReadFull(wallet.BalanceSize)
+	if r.Err != nil {
+		return r.Err
 	}
-
-	b.Destination = make([]byte, wallet.AddressSize)
-	if _, err = reader.Read(b.Destination); err != nil {
+	if err := b.Balance.UnmarshalBinary(balance); err != nil {
 		return err
 	}
 
-	balance := make([]byte, wallet.BalanceSize)
-	if _, err = reader.Read(balance); err != nil {
-		return err
-	}
-	if err = b.Balance.UnmarshalBinary(balance); err != nil {
-		return err
-	}
-
-	commonBytes := make([]byte, reader.Len())
-	if _, err = reader.Read(commonBytes); err != nil {
-		return err
-	}
-
-	return b.Common.UnmarshalBinary(commonBytes)
+	return b.Common.UnmarshalBinary(r.ReadFull(reader.Len()))
 }
 
 func (b *SendBlock) Hash() Hash {
@@ -301,22 +271,21 @@ func (b *SendBlock) Valid() bool {
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (b *ReceiveBlock) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
 
-	var err error
-	if _, err = buf.Write(b.PreviousHash[:]); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.SourceHash[:]); err != nil {
-		return nil, err
+	w.WriteBytes(b.PreviousHash[:])
+	w.WriteBytes(b.SourceHash[:])
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	commonBytes, err := b.Common.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	if _, err = buf.Write(commonBytes); err != nil {
-		return nil, err
+	w.WriteBytes(commonBytes)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	return buf.Bytes(), nil
@@ -325,22 +294,15 @@ func (b *ReceiveBlock) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (b *ReceiveBlock) UnmarshalBinary(data []byte) error {
 	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
 
-	var err error
-	if _, err = reader.Read(b.PreviousHash[:]); err != nil {
-		return err
-	}
-
-	if _, err = reader.Read(b.SourceHash[:]); err != nil {
-		return err
-	}
-
-	commonBytes := make([]byte, reader.Len())
-	if _, err = reader.Read(commonBytes); err != nil {
-		return err
+	r.ReadBytes(b.PreviousHash[:])
+	r.ReadBytes(b.SourceHash[:])
+	if r.Err != nil {
+		return r.Err
 	}
 
-	return b.Common.UnmarshalBinary(commonBytes)
+	return b.Common.UnmarshalBinary(r.ReadFull(reader.Len()))
 }
 
 func (b *ReceiveBlock) Hash() Hash {
@@ -370,22 +332,21 @@ func (b *ReceiveBlock) Valid() bool {
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 func (b *ChangeBlock) MarshalBinary() ([]byte, error) {
 	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
 
-	var err error
-	if _, err = buf.Write(b.PreviousHash[:]); err != nil {
-		return nil, err
-	}
-
-	if _, err = buf.Write(b.Representative); err != nil {
-		return nil, err
+	w.WriteBytes(b.PreviousHash[:])
+	w.WriteBytes(b.Representative)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	commonBytes, err := b.Common.MarshalBinary()
 	if err != nil {
 		return nil, err
 	}
-	if _, err = buf.Write(commonBytes); err != nil {
-		return nil, err
+	w.WriteBytes(commonBytes)
+	if w.Err != nil {
+		return nil, w.Err
 	}
 
 	return buf.Bytes(), nil
@@ -394,23 +355,15 @@ func (b *ChangeBlock) MarshalBinary() ([]byte, error) {
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
 func (b *ChangeBlock) UnmarshalBinary(data []byte) error {
 	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
 
-	var err error
-	if _, err = reader.Read(b.PreviousHash[:]); err != nil {
-		return err
-	}
-
-	b.Representative = make([]byte, wallet.AddressSize)
-	if _, err = reader.Read(b.Representative); err != nil {
-		return err
-	}
-
-	commonBytes := make([]byte, reader.Len())
-	if _, err = reader.Read(commonBytes); err != nil {
-		return err
+	r.ReadBytes(b.PreviousHash[:])
+	b.Representative = r.ReadFull(wallet.AddressSize)
+	if r.Err != nil {
+		return r.Err
 	}
 
-	return b.Common.UnmarshalBinary(commonBytes)
+	return b.Common.UnmarshalBinary(r.ReadFull(reader.Len()))
 }
 
 func (b *ChangeBlock) Hash() Hash {
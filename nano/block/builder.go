@@ -0,0 +1,139 @@
+package block
+
+import (
+	"crypto/ed25519"
+	"errors"
+
+	"github.com/alexbakker/gonano/nano/wallet"
+)
+
+var (
+	ErrBuilderBadSignature = errors.New("block: signature does not match account")
+	ErrBuilderBadWork      = errors.New("block: work does not meet the required difficulty")
+)
+
+// WorkFunc computes a valid Work value for the given root hash.
+type WorkFunc func(root Hash) (Work, error)
+
+// Builder assembles a StateBlock field by field and produces a signed,
+// worked Block once Build is called. All state-backed block helpers
+// (Send, Receive, Open, Change) funnel through it.
+type Builder struct {
+	block *StateBlock
+	priv  wallet.PrivateKey
+	work  WorkFunc
+}
+
+// NewStateBuilder starts building a state block for the given account.
+func NewStateBuilder(account wallet.Address) *Builder {
+	return &Builder{block: &StateBlock{Account: account}}
+}
+
+func (b *Builder) Previous(previous Hash) *Builder {
+	b.block.Previous = previous
+	return b
+}
+
+func (b *Builder) Representative(rep wallet.Address) *Builder {
+	b.block.Representative = rep
+	return b
+}
+
+func (b *Builder) Balance(balance wallet.Balance) *Builder {
+	b.block.Balance = balance
+	return b
+}
+
+func (b *Builder) Link(link Hash) *Builder {
+	b.block.Link = link
+	return b
+}
+
+// Sign sets the private key used to sign the block in Build.
+func (b *Builder) Sign(priv wallet.PrivateKey) *Builder {
+	b.priv = priv
+	return b
+}
+
+// Work sets the function used to generate the block's proof of work in
+// Build.
+func (b *Builder) Work(work WorkFunc) *Builder {
+	b.work = work
+	return b
+}
+
+// Build finalizes the block: it generates its proof of work, signs it,
+// and refuses to return a block whose work or signature doesn't
+// validate against the account.
+func (b *Builder) Build() (*StateBlock, error) {
+	root := b.block.Root()
+
+	if b.work != nil {
+		work, err := b.work(root)
+		if err != nil {
+			return nil, err
+		}
+		b.block.Common.Work = work
+	}
+
+	if !b.block.Common.Work.Valid(root) {
+		return nil, ErrBuilderBadWork
+	}
+
+	hash := b.block.Hash()
+	if b.priv != nil {
+		sig := ed25519.Sign(ed25519.PrivateKey(b.priv), hash[:])
+		copy(b.block.Common.Signature[:], sig)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(b.block.Account), hash[:], b.block.Common.Signature[:]) {
+		return nil, ErrBuilderBadSignature
+	}
+
+	return b.block, nil
+}
+
+// Send builds a state block that sends amount from the from account's
+// current balance to the to account. representative is required because
+// a state block always encodes the account's representative, even when
+// it isn't changing.
+func Send(from, to, representative wallet.Address, amount wallet.Balance, previous Hash, currentBalance wallet.Balance) *Builder {
+	var link Hash
+	copy(link[:], to)
+
+	return NewStateBuilder(from).
+		Previous(previous).
+		Representative(representative).
+		Balance(currentBalance.Sub(amount)).
+		Link(link)
+}
+
+// Receive builds a state block that receives amount from source into
+// the account, on top of its current balance. representative is
+// required because a state block always encodes the account's
+// representative, even when it isn't changing.
+func Receive(account, representative wallet.Address, previous, source Hash, currentBalance, amount wallet.Balance) *Builder {
+	return NewStateBuilder(account).
+		Previous(previous).
+		Representative(representative).
+		Balance(currentBalance.Add(amount)).
+		Link(source)
+}
+
+// Open builds the first state block for an account, receiving from
+// source. The caller still needs to chain Balance with the amount
+// received, since Open has no prior block to read it from.
+func Open(source Hash, representative, account wallet.Address) *Builder {
+	return NewStateBuilder(account).
+		Representative(representative).
+		Link(source)
+}
+
+// Change builds a state block that changes the account's representative
+// without moving any funds.
+func Change(account wallet.Address, previous Hash, representative wallet.Address, balance wallet.Balance) *Builder {
+	return NewStateBuilder(account).
+		Previous(previous).
+		Representative(representative).
+		Balance(balance)
+}
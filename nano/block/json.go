@@ -0,0 +1,386 @@
+package block
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/alexbakker/gonano/nano/wallet"
+)
+
+var ErrBadBlockJSON = errors.New("bad block json")
+
+// jsonBlockNames holds the lowercase "type" strings nano_node's RPC
+// uses, as opposed to the uppercase ones Name() returns for logging.
+var jsonBlockNames = map[byte]string{
+	idBlockSend:    "send",
+	idBlockReceive: "receive",
+	idBlockOpen:    "open",
+	idBlockChange:  "change",
+	idBlockState:   "state",
+}
+
+func hexEncode(b []byte) string {
+	return strings.ToUpper(hex.EncodeToString(b))
+}
+
+func hexDecode(dst []byte, s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return ErrBadBlockJSON
+	}
+	copy(dst, b)
+	return nil
+}
+
+// workJSON renders work the way nano_node's RPC does: big-endian hex,
+// regardless of the byte order the block type's binary encoding uses.
+func workJSON(w Work) string {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], uint64(w))
+	return hexEncode(buf[:])
+}
+
+func parseWorkJSON(s string) (Work, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, ErrBadBlockJSON
+	}
+	return Work(binary.BigEndian.Uint64(b)), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *OpenBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type           string `json:"type"`
+		Source         string `json:"source"`
+		Representative string `json:"representative"`
+		Account        string `json:"account"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{
+		Type:           jsonBlockNames[idBlockOpen],
+		Source:         hexEncode(b.SourceHash[:]),
+		Representative: b.Representative.String(),
+		Account:        b.Address.String(),
+		Work:           workJSON(b.Common.Work),
+		Signature:      hexEncode(b.Common.Signature[:]),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *OpenBlock) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Source         string `json:"source"`
+		Representative string `json:"representative"`
+		Account        string `json:"account"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.SourceHash[:], aux.Source); err != nil {
+		return err
+	}
+
+	rep, err := wallet.AddressFromString(aux.Representative)
+	if err != nil {
+		return err
+	}
+	b.Representative = rep
+
+	account, err := wallet.AddressFromString(aux.Account)
+	if err != nil {
+		return err
+	}
+	b.Address = account
+
+	work, err := parseWorkJSON(aux.Work)
+	if err != nil {
+		return err
+	}
+	b.Common.Work = work
+
+	return hexDecode(b.Common.Signature[:], aux.Signature)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *SendBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type        string `json:"type"`
+		Previous    string `json:"previous"`
+		Destination string `json:"destination"`
+		Balance     string `json:"balance"`
+		Work        string `json:"work"`
+		Signature   string `json:"signature"`
+	}{
+		Type:        jsonBlockNames[idBlockSend],
+		Previous:    hexEncode(b.PreviousHash[:]),
+		Destination: b.Destination.String(),
+		Balance:     hexEncode(b.Balance.Bytes(binary.BigEndian)),
+		Work:        workJSON(b.Common.Work),
+		Signature:   hexEncode(b.Common.Signature[:]),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *SendBlock) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Previous    string `json:"previous"`
+		Destination string `json:"destination"`
+		Balance     string `json:"balance"`
+		Work        string `json:"work"`
+		Signature   string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.PreviousHash[:], aux.Previous); err != nil {
+		return err
+	}
+
+	dest, err := wallet.AddressFromString(aux.Destination)
+	if err != nil {
+		return err
+	}
+	b.Destination = dest
+
+	balance, err := hex.DecodeString(aux.Balance)
+	if err != nil {
+		return err
+	}
+	if err := b.Balance.UnmarshalBinary(balance); err != nil {
+		return err
+	}
+
+	work, err := parseWorkJSON(aux.Work)
+	if err != nil {
+		return err
+	}
+	b.Common.Work = work
+
+	return hexDecode(b.Common.Signature[:], aux.Signature)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *ReceiveBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type      string `json:"type"`
+		Previous  string `json:"previous"`
+		Source    string `json:"source"`
+		Work      string `json:"work"`
+		Signature string `json:"signature"`
+	}{
+		Type:      jsonBlockNames[idBlockReceive],
+		Previous:  hexEncode(b.PreviousHash[:]),
+		Source:    hexEncode(b.SourceHash[:]),
+		Work:      workJSON(b.Common.Work),
+		Signature: hexEncode(b.Common.Signature[:]),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *ReceiveBlock) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Previous  string `json:"previous"`
+		Source    string `json:"source"`
+		Work      string `json:"work"`
+		Signature string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.PreviousHash[:], aux.Previous); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.SourceHash[:], aux.Source); err != nil {
+		return err
+	}
+
+	work, err := parseWorkJSON(aux.Work)
+	if err != nil {
+		return err
+	}
+	b.Common.Work = work
+
+	return hexDecode(b.Common.Signature[:], aux.Signature)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *ChangeBlock) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Type           string `json:"type"`
+		Previous       string `json:"previous"`
+		Representative string `json:"representative"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{
+		Type:           jsonBlockNames[idBlockChange],
+		Previous:       hexEncode(b.PreviousHash[:]),
+		Representative: b.Representative.String(),
+		Work:           workJSON(b.Common.Work),
+		Signature:      hexEncode(b.Common.Signature[:]),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *ChangeBlock) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Previous       string `json:"previous"`
+		Representative string `json:"representative"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.PreviousHash[:], aux.Previous); err != nil {
+		return err
+	}
+
+	rep, err := wallet.AddressFromString(aux.Representative)
+	if err != nil {
+		return err
+	}
+	b.Representative = rep
+
+	work, err := parseWorkJSON(aux.Work)
+	if err != nil {
+		return err
+	}
+	b.Common.Work = work
+
+	return hexDecode(b.Common.Signature[:], aux.Signature)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (b *StateBlock) MarshalJSON() ([]byte, error) {
+	var link wallet.Address = make([]byte, wallet.AddressSize)
+	copy(link, b.Link[:])
+
+	return json.Marshal(&struct {
+		Type           string `json:"type"`
+		Account        string `json:"account"`
+		Previous       string `json:"previous"`
+		Representative string `json:"representative"`
+		Balance        string `json:"balance"`
+		Link           string `json:"link"`
+		LinkAsAccount  string `json:"link_as_account"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{
+		Type:           jsonBlockNames[idBlockState],
+		Account:        b.Account.String(),
+		Previous:       hexEncode(b.Previous[:]),
+		Representative: b.Representative.String(),
+		Balance:        b.Balance.String(),
+		Link:           hexEncode(b.Link[:]),
+		LinkAsAccount:  link.String(),
+		Work:           workJSON(b.Common.Work),
+		Signature:      hexEncode(b.Common.Signature[:]),
+	})
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (b *StateBlock) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Account        string `json:"account"`
+		Previous       string `json:"previous"`
+		Representative string `json:"representative"`
+		Balance        string `json:"balance"`
+		Link           string `json:"link"`
+		Work           string `json:"work"`
+		Signature      string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	account, err := wallet.AddressFromString(aux.Account)
+	if err != nil {
+		return err
+	}
+	b.Account = account
+
+	if err := hexDecode(b.Previous[:], aux.Previous); err != nil {
+		return err
+	}
+
+	rep, err := wallet.AddressFromString(aux.Representative)
+	if err != nil {
+		return err
+	}
+	b.Representative = rep
+
+	if err := b.Balance.UnmarshalText([]byte(aux.Balance)); err != nil {
+		return err
+	}
+
+	if err := hexDecode(b.Link[:], aux.Link); err != nil {
+		return err
+	}
+
+	work, err := parseWorkJSON(aux.Work)
+	if err != nil {
+		return err
+	}
+	b.Common.Work = work
+
+	return hexDecode(b.Common.Signature[:], aux.Signature)
+}
+
+// Wrapper wraps a Block so that it can be marshaled to and unmarshaled
+// from the JSON shape used by nano_node's RPC, without the caller having
+// to know the concrete block type ahead of time.
+type Wrapper struct {
+	Block
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (w *Wrapper) MarshalJSON() ([]byte, error) {
+	return json.Marshal(w.Block)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (w *Wrapper) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var id byte
+	for candidate, name := range jsonBlockNames {
+		if strings.EqualFold(name, aux.Type) {
+			id = candidate
+			break
+		}
+	}
+
+	block, err := New(id)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, block); err != nil {
+		return err
+	}
+
+	w.Block = block
+	return nil
+}
@@ -0,0 +1,133 @@
+package block
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/alexbakker/gonano/nano/internal/binio"
+	"github.com/alexbakker/gonano/nano/wallet"
+)
+
+const blockSizeState = blockSizeCommon + wallet.AddressSize*2 + HashSize*2 + 16
+
+// StateSubtype describes the semantic meaning of a state block, inferred
+// from how it relates to the account's previous state block.
+type StateSubtype int
+
+const (
+	StateSubtypeSend StateSubtype = iota
+	StateSubtypeReceive
+	StateSubtypeChange
+	StateSubtypeOpen
+	StateSubtypeEpoch
+)
+
+// stateBlockPreamble is hashed ahead of the state block's fields, as
+// mandated by the state block hashing scheme. The last byte is the
+// block's type ID.
+var stateBlockPreamble = [32]byte{31: idBlockState}
+
+type StateBlock struct {
+	Account        wallet.Address
+	Previous       Hash
+	Representative wallet.Address
+	Balance        wallet.Balance
+	Link           Hash
+	Common         CommonBlock
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (b *StateBlock) MarshalBinary() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	w := binio.NewWriter(buf)
+
+	w.WriteBytes(b.Account)
+	w.WriteBytes(b.Previous[:])
+	w.WriteBytes(b.Representative)
+	w.WriteBytes(b.Balance.Bytes(binary.BigEndian))
+	w.WriteBytes(b.Link[:])
+	w.WriteBytes(b.Common.Signature[:])
+	// Unlike every other block, nano_node serializes state block work
+	// big-endian, so this can't be delegated to CommonBlock's
+	// little-endian MarshalBinary.
+	w.WriteU64BE(uint64(b.Common.Work))
+	if w.Err != nil {
+		return nil, w.Err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+func (b *StateBlock) UnmarshalBinary(data []byte) error {
+	reader := bytes.NewReader(data)
+	r := binio.NewReader(reader)
+
+	b.Account = r.ReadFull(wallet.AddressSize)
+	r.ReadBytes(b.Previous[:])
+	b.Representative = r.ReadFull(wallet.AddressSize)
+	balance := r.ReadFull(wallet.BalanceSize)
+	r.ReadBytes(b.Link[:])
+	r.ReadBytes(b.Common.Signature[:])
+	// See the big-endian note in MarshalBinary.
+	b.Common.Work = Work(r.ReadU64BE())
+	if r.Err != nil {
+		return r.Err
+	}
+
+	return b.Balance.UnmarshalBinary(balance)
+}
+
+func (b *StateBlock) Hash() Hash {
+	return hashBytes(stateBlockPreamble[:], b.Account, b.Previous[:],
+		b.Representative, b.Balance.Bytes(binary.BigEndian), b.Link[:])
+}
+
+func (b *StateBlock) Root() Hash {
+	var zero Hash
+	if b.Previous != zero {
+		return b.Previous
+	}
+
+	var hash Hash
+	copy(hash[:], b.Account)
+	return hash
+}
+
+func (b *StateBlock) Signature() Signature {
+	return b.Common.Signature
+}
+
+func (b *StateBlock) Size() int {
+	return blockSizeState
+}
+
+func (b *StateBlock) ID() byte {
+	return idBlockState
+}
+
+func (b *StateBlock) Valid() bool {
+	return b.Common.Work.Valid(b.Root())
+}
+
+// Subtype classifies the block by comparing it against the balance the
+// account held before this block, since the state block format itself
+// doesn't distinguish sends from receives from changes.
+func (b *StateBlock) Subtype(prevBalance wallet.Balance) StateSubtype {
+	var zero Hash
+	if b.Previous == zero {
+		return StateSubtypeOpen
+	}
+
+	switch b.Balance.Cmp(prevBalance) {
+	case 1:
+		return StateSubtypeReceive
+	case -1:
+		return StateSubtypeSend
+	default:
+		if b.Link == zero {
+			return StateSubtypeChange
+		}
+		return StateSubtypeEpoch
+	}
+}
@@ -0,0 +1,79 @@
+package block
+
+import (
+	"fmt"
+	"io"
+)
+
+// Decoder reads a stream of back-to-back blocks off an io.Reader, such
+// as a bootstrap or realtime frontier stream, without buffering more
+// than a single block at a time.
+type Decoder struct {
+	r io.Reader
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r}
+}
+
+// Decode reads and returns the next block in the stream. It returns
+// io.EOF once the stream ends cleanly on a frame boundary.
+func (d *Decoder) Decode() (Block, error) {
+	var idBuf [1]byte
+	if _, err := io.ReadFull(d.r, idBuf[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	// not_a_block is the standard terminator bulk_pull/frontier streams
+	// send instead of closing the connection.
+	if idBuf[0] == idBlockNotABlock {
+		return nil, io.EOF
+	}
+
+	block, err := New(idBuf[0])
+	if err != nil {
+		return nil, fmt.Errorf("decode block type: %w", err)
+	}
+
+	body := make([]byte, block.Size())
+	if _, err := io.ReadFull(d.r, body); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, fmt.Errorf("decode block body: %w", err)
+	}
+
+	if err := block.UnmarshalBinary(body); err != nil {
+		return nil, fmt.Errorf("decode block body: %w", err)
+	}
+
+	return block, nil
+}
+
+// Encoder writes a stream of back-to-back blocks to an io.Writer in the
+// same frame format Decoder reads: a type byte followed by the
+// marshaled body.
+type Encoder struct {
+	w io.Writer
+}
+
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+func (e *Encoder) Encode(block Block) error {
+	if _, err := e.w.Write([]byte{block.ID()}); err != nil {
+		return err
+	}
+
+	body, err := block.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(body)
+	return err
+}
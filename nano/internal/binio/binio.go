@@ -0,0 +1,103 @@
+// Package binio provides small error-absorbing wrappers around io.Reader
+// and io.Writer for decoding and encoding the fixed-size binary formats
+// used throughout the nano package. Instead of checking an error after
+// every field, callers issue a sequence of reads or writes and check
+// Err once at the end.
+package binio
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Reader reads a sequence of fixed-size fields from an underlying
+// io.Reader, stopping at (and remembering) the first error encountered.
+type Reader struct {
+	r   io.Reader
+	Err error
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// ReadBytes reads len(p) bytes into p.
+func (r *Reader) ReadBytes(p []byte) {
+	if r.Err != nil {
+		return
+	}
+	_, r.Err = io.ReadFull(r.r, p)
+}
+
+// ReadFull reads and returns exactly size bytes.
+func (r *Reader) ReadFull(size int) []byte {
+	p := make([]byte, size)
+	r.ReadBytes(p)
+	return p
+}
+
+func (r *Reader) ReadU64LE() uint64 {
+	if r.Err != nil {
+		return 0
+	}
+	var buf [8]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		r.Err = err
+		return 0
+	}
+	return binary.LittleEndian.Uint64(buf[:])
+}
+
+// ReadU64BE reads a big-endian uint64, for the handful of fields (e.g.
+// state block work) that nano_node serializes big-endian unlike the
+// rest of the wire format.
+func (r *Reader) ReadU64BE() uint64 {
+	if r.Err != nil {
+		return 0
+	}
+	var buf [8]byte
+	if _, err := io.ReadFull(r.r, buf[:]); err != nil {
+		r.Err = err
+		return 0
+	}
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// Writer writes a sequence of fixed-size fields to an underlying
+// io.Writer, stopping at (and remembering) the first error encountered.
+type Writer struct {
+	w   io.Writer
+	Err error
+}
+
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+func (w *Writer) WriteBytes(p []byte) {
+	if w.Err != nil {
+		return
+	}
+	_, w.Err = w.w.Write(p)
+}
+
+func (w *Writer) WriteU64LE(v uint64) {
+	if w.Err != nil {
+		return
+	}
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	_, w.Err = w.w.Write(buf[:])
+}
+
+// WriteU64BE writes a big-endian uint64, for the handful of fields (e.g.
+// state block work) that nano_node serializes big-endian unlike the
+// rest of the wire format.
+func (w *Writer) WriteU64BE(v uint64) {
+	if w.Err != nil {
+		return
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	_, w.Err = w.w.Write(buf[:])
+}